@@ -0,0 +1,49 @@
+// Package mysql provides the MySQL-specific sql.ColumnMapper.
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/Fs02/grimoire/adapter/sql"
+)
+
+// ColumnMapper maps grimoire column types to their MySQL equivalent,
+// for use as sql.Config.ColumnMapper.
+func ColumnMapper(def sql.ColumnDefinition) (string, string) {
+	switch def.Type {
+	case sql.Int:
+		if def.AutoIncrement {
+			return "int", "AUTO_INCREMENT"
+		}
+		return "int", ""
+	case sql.BigInt:
+		if def.AutoIncrement {
+			return "bigint", "AUTO_INCREMENT"
+		}
+		return "bigint", ""
+	case sql.String:
+		if def.Limit > 0 {
+			return fmt.Sprintf("varchar(%d)", def.Limit), ""
+		}
+		return "varchar(255)", ""
+	case sql.Text:
+		return "text", ""
+	case sql.Bool:
+		return "boolean", ""
+	case sql.DateTime:
+		return "datetime", ""
+	case sql.Decimal:
+		return "decimal", ""
+	case sql.JSON:
+		return "json", ""
+	default:
+		return string(def.Type), ""
+	}
+}
+
+// DropIndexStatement builds a MySQL DROP INDEX statement, for use as
+// sql.Config.DropIndexStatement. Unlike postgres/sqlite3, MySQL requires
+// the owning table: DROP INDEX name ON table.
+func DropIndexStatement(table, name string) string {
+	return fmt.Sprintf("DROP INDEX %s ON %s;", name, table)
+}
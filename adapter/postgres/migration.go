@@ -0,0 +1,43 @@
+// Package postgres provides the postgres-specific sql.ColumnMapper.
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/Fs02/grimoire/adapter/sql"
+)
+
+// ColumnMapper maps grimoire column types to their postgres equivalent,
+// for use as sql.Config.ColumnMapper. Auto-increment columns use the
+// serial/bigserial types instead of a trailing clause.
+func ColumnMapper(def sql.ColumnDefinition) (string, string) {
+	switch def.Type {
+	case sql.Int:
+		if def.AutoIncrement {
+			return "serial", ""
+		}
+		return "integer", ""
+	case sql.BigInt:
+		if def.AutoIncrement {
+			return "bigserial", ""
+		}
+		return "bigint", ""
+	case sql.String:
+		if def.Limit > 0 {
+			return fmt.Sprintf("varchar(%d)", def.Limit), ""
+		}
+		return "varchar(255)", ""
+	case sql.Text:
+		return "text", ""
+	case sql.Bool:
+		return "boolean", ""
+	case sql.DateTime:
+		return "timestamp", ""
+	case sql.Decimal:
+		return "decimal", ""
+	case sql.JSON:
+		return "jsonb", ""
+	default:
+		return string(def.Type), ""
+	}
+}
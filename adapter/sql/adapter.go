@@ -2,9 +2,10 @@
 package sql
 
 import (
+	"context"
 	"database/sql"
 	"errors"
-	"strconv"
+	"sync"
 	"time"
 
 	"github.com/Fs02/grimoire"
@@ -18,25 +19,83 @@ type Config struct {
 	EscapeChar          string
 	ErrorFunc           func(error) error
 	IncrementFunc       func(Adapter) int
+	ColumnMapper        ColumnMapper
+	StmtCacheSize       int
+	Logger              Logger
+	SlowThreshold       time.Duration
+	LogSampleRate       int
+	MaxOpenConns        int
+	MaxIdleConns        int
+	ConnMaxLifetime     time.Duration
+	ConnMaxIdleTime     time.Duration
+	HeartbeatInterval   time.Duration
+	OnDisconnect        func(error)
+	DropIndexStatement  func(table, name string) string
 }
 
 // Adapter definition for mysql database.
 type Adapter struct {
-	Config    *Config
-	DB        *sql.DB
-	Tx        *sql.Tx
-	savepoint int
+	Config *Config
+	DB     *sql.DB
+	Tx     *sql.Tx
+
+	stmts     *stmtCache
+	stmtsOnce sync.Once
+
+	sampleTick    int64
+	heartbeatDone chan struct{}
+
+	txState       *txState
+	savepointName string
+	status        txStatus
+	statusMu      sync.Mutex
 }
 
 var _ grimoire.Adapter = (*Adapter)(nil)
 
 // Close mysql connection.
 func (adapter *Adapter) Close() error {
+	if adapter.heartbeatDone != nil {
+		close(adapter.heartbeatDone)
+		adapter.heartbeatDone = nil
+	}
+
+	if adapter.stmts != nil {
+		adapter.stmts.close()
+	}
+
 	return adapter.DB.Close()
 }
 
+// stmtCacheFor lazily creates the prepared statement cache for this
+// adapter, bound to its Tx if any or its DB otherwise. It returns nil
+// when Config.StmtCacheSize is 0 (the default), leaving the cache
+// disabled. The lazy init runs at most once per Adapter (guarded by
+// stmtsOnce) since the root Adapter is a long-lived object shared by
+// concurrent Query/Exec/Aggregate calls.
+func (adapter *Adapter) stmtCacheFor() *stmtCache {
+	adapter.stmtsOnce.Do(func() {
+		var preparer stmtPreparer
+		if adapter.Tx != nil {
+			preparer = adapter.Tx
+		} else {
+			preparer = adapter.DB
+		}
+
+		adapter.stmts = newStmtCache(preparer, adapter.Config.StmtCacheSize)
+	})
+
+	return adapter.stmts
+}
+
 // Aggregate record using given query.
 func (adapter *Adapter) Aggregate(query grimoire.Query, mode string, field string, loggers ...grimoire.Logger) (int, error) {
+	return adapter.AggregateContext(context.Background(), query, mode, field, loggers...)
+}
+
+// AggregateContext record using given query, honoring ctx cancellation
+// and deadlines.
+func (adapter *Adapter) AggregateContext(ctx context.Context, query grimoire.Query, mode string, field string, loggers ...grimoire.Logger) (int, error) {
 	var (
 		err             error
 		out             sql.NullInt64
@@ -44,12 +103,25 @@ func (adapter *Adapter) Aggregate(query grimoire.Query, mode string, field strin
 	)
 
 	start := time.Now()
-	if adapter.Tx != nil {
-		err = adapter.Tx.QueryRow(statement, args...).Scan(&out)
+	if cache := adapter.stmtCacheFor(); cache != nil {
+		var stmt *sql.Stmt
+		if stmt, err = cache.prepare(ctx, statement); err == nil {
+			err = stmt.QueryRowContext(ctx, args...).Scan(&out)
+		}
+	} else if adapter.Tx != nil {
+		err = adapter.Tx.QueryRowContext(ctx, statement, args...).Scan(&out)
 	} else {
-		err = adapter.DB.QueryRow(statement, args...).Scan(&out)
+		err = adapter.DB.QueryRowContext(ctx, statement, args...).Scan(&out)
 	}
 
+	adapter.log(LogEntry{
+		Statement:  statement,
+		Args:       args,
+		Duration:   time.Since(start),
+		Err:        err,
+		Operation:  OpQuery,
+		Collection: query.Collection,
+	})
 	go grimoire.Log(loggers, statement, time.Since(start), err)
 
 	return int(out.Int64), err
@@ -57,6 +129,12 @@ func (adapter *Adapter) Aggregate(query grimoire.Query, mode string, field strin
 
 // Query performs query operation.
 func (adapter *Adapter) Query(query grimoire.Query, loggers ...grimoire.Logger) (grimoire.Cursor, error) {
+	return adapter.QueryContext(context.Background(), query, loggers...)
+}
+
+// QueryContext performs query operation, honoring ctx cancellation and
+// deadlines.
+func (adapter *Adapter) QueryContext(ctx context.Context, query grimoire.Query, loggers ...grimoire.Logger) (grimoire.Cursor, error) {
 	var (
 		rows            *sql.Rows
 		err             error
@@ -64,12 +142,25 @@ func (adapter *Adapter) Query(query grimoire.Query, loggers ...grimoire.Logger)
 	)
 
 	start := time.Now()
-	if adapter.Tx != nil {
-		rows, err = adapter.Tx.Query(statement, args...)
+	if cache := adapter.stmtCacheFor(); cache != nil {
+		var stmt *sql.Stmt
+		if stmt, err = cache.prepare(ctx, statement); err == nil {
+			rows, err = stmt.QueryContext(ctx, args...)
+		}
+	} else if adapter.Tx != nil {
+		rows, err = adapter.Tx.QueryContext(ctx, statement, args...)
 	} else {
-		rows, err = adapter.DB.Query(statement, args...)
+		rows, err = adapter.DB.QueryContext(ctx, statement, args...)
 	}
 
+	adapter.log(LogEntry{
+		Statement:  statement,
+		Args:       args,
+		Duration:   time.Since(start),
+		Err:        err,
+		Operation:  OpQuery,
+		Collection: query.Collection,
+	})
 	go grimoire.Log(loggers, statement, time.Since(start), err)
 
 	return &Cursor{rows}, adapter.Config.ErrorFunc(err)
@@ -77,35 +168,64 @@ func (adapter *Adapter) Query(query grimoire.Query, loggers ...grimoire.Logger)
 
 // Exec performs exec operation.
 func (adapter *Adapter) Exec(statement string, args []interface{}, loggers ...grimoire.Logger) (int64, int64, error) {
+	return adapter.ExecContext(context.Background(), statement, args, loggers...)
+}
+
+// ExecContext performs exec operation, honoring ctx cancellation and
+// deadlines.
+func (adapter *Adapter) ExecContext(ctx context.Context, statement string, args []interface{}, loggers ...grimoire.Logger) (int64, int64, error) {
 	var (
 		res sql.Result
 		err error
 	)
 
 	start := time.Now()
-	if adapter.Tx != nil {
-		res, err = adapter.Tx.Exec(statement, args...)
+	if cache := adapter.stmtCacheFor(); cache != nil {
+		var stmt *sql.Stmt
+		if stmt, err = cache.prepare(ctx, statement); err == nil {
+			res, err = stmt.ExecContext(ctx, args...)
+		}
+	} else if adapter.Tx != nil {
+		res, err = adapter.Tx.ExecContext(ctx, statement, args...)
 	} else {
-		res, err = adapter.DB.Exec(statement, args...)
+		res, err = adapter.DB.ExecContext(ctx, statement, args...)
+	}
+
+	var lastID, rowCount int64
+	if err == nil {
+		lastID, _ = res.LastInsertId()
+		rowCount, _ = res.RowsAffected()
 	}
 
+	adapter.log(LogEntry{
+		Statement:    statement,
+		Args:         args,
+		Duration:     time.Since(start),
+		Err:          err,
+		Operation:    OpExec,
+		RowsAffected: rowCount,
+		LastInsertID: lastID,
+	})
 	go grimoire.Log(loggers, statement, time.Since(start), err)
 
 	if err != nil {
 		return 0, 0, adapter.Config.ErrorFunc(err)
 	}
 
-	lastID, _ := res.LastInsertId()
-	rowCount, _ := res.RowsAffected()
-
 	return lastID, rowCount, nil
 }
 
 // Insert inserts a record to database and returns its id.
 func (adapter *Adapter) Insert(query grimoire.Query, changes grimoire.Changes, loggers ...grimoire.Logger) (interface{}, error) {
+	return adapter.InsertContext(context.Background(), query, changes, loggers...)
+}
+
+// InsertContext inserts a record to database and returns its id,
+// honoring ctx cancellation and deadlines.
+func (adapter *Adapter) InsertContext(ctx context.Context, query grimoire.Query, changes grimoire.Changes, loggers ...grimoire.Logger) (interface{}, error) {
 	var (
 		statement, args = NewBuilder(adapter.Config).Insert(query.Collection, changes)
-		id, _, err      = adapter.Exec(statement, args, loggers...)
+		id, _, err      = adapter.ExecContext(ctx, statement, args, loggers...)
 	)
 
 	return id, err
@@ -113,8 +233,14 @@ func (adapter *Adapter) Insert(query grimoire.Query, changes grimoire.Changes, l
 
 // InsertAll inserts all record to database and returns its ids.
 func (adapter *Adapter) InsertAll(query grimoire.Query, fields []string, allchanges []grimoire.Changes, loggers ...grimoire.Logger) ([]interface{}, error) {
+	return adapter.InsertAllContext(context.Background(), query, fields, allchanges, loggers...)
+}
+
+// InsertAllContext inserts all record to database and returns its ids,
+// honoring ctx cancellation and deadlines.
+func (adapter *Adapter) InsertAllContext(ctx context.Context, query grimoire.Query, fields []string, allchanges []grimoire.Changes, loggers ...grimoire.Logger) ([]interface{}, error) {
 	statement, args := NewBuilder(adapter.Config).InsertAll(query.Collection, fields, allchanges)
-	id, _, err := adapter.Exec(statement, args, loggers...)
+	id, _, err := adapter.ExecContext(ctx, statement, args, loggers...)
 	if err != nil {
 		return nil, err
 	}
@@ -137,9 +263,15 @@ func (adapter *Adapter) InsertAll(query grimoire.Query, fields []string, allchan
 
 // Update updates a record in database.
 func (adapter *Adapter) Update(query grimoire.Query, changes grimoire.Changes, loggers ...grimoire.Logger) error {
+	return adapter.UpdateContext(context.Background(), query, changes, loggers...)
+}
+
+// UpdateContext updates a record in database, honoring ctx cancellation
+// and deadlines.
+func (adapter *Adapter) UpdateContext(ctx context.Context, query grimoire.Query, changes grimoire.Changes, loggers ...grimoire.Logger) error {
 	var (
 		statement, args = NewBuilder(adapter.Config).Update(query.Collection, changes, query.WhereQuery)
-		_, _, err       = adapter.Exec(statement, args, loggers...)
+		_, _, err       = adapter.ExecContext(ctx, statement, args, loggers...)
 	)
 
 	return err
@@ -147,9 +279,15 @@ func (adapter *Adapter) Update(query grimoire.Query, changes grimoire.Changes, l
 
 // Delete deletes all results that match the query.
 func (adapter *Adapter) Delete(query grimoire.Query, loggers ...grimoire.Logger) error {
+	return adapter.DeleteContext(context.Background(), query, loggers...)
+}
+
+// DeleteContext deletes all results that match the query, honoring ctx
+// cancellation and deadlines.
+func (adapter *Adapter) DeleteContext(ctx context.Context, query grimoire.Query, loggers ...grimoire.Logger) error {
 	var (
 		statement, args = NewBuilder(adapter.Config).Delete(query.Collection, query.WhereQuery)
-		_, _, err       = adapter.Exec(statement, args, loggers...)
+		_, _, err       = adapter.ExecContext(ctx, statement, args, loggers...)
 	)
 
 	return err
@@ -157,37 +295,65 @@ func (adapter *Adapter) Delete(query grimoire.Query, loggers ...grimoire.Logger)
 
 // Begin begins a new transaction.
 func (adapter *Adapter) Begin() (grimoire.Adapter, error) {
+	return adapter.BeginContext(context.Background(), nil)
+}
+
+// BeginContext begins a new transaction, honoring ctx cancellation and
+// deadlines. opts controls the isolation level and read-only mode of a
+// top-level transaction; it is ignored for nested savepoints, which
+// always inherit the parent transaction's settings.
+func (adapter *Adapter) BeginContext(ctx context.Context, opts *sql.TxOptions) (grimoire.Adapter, error) {
 	var (
-		tx        *sql.Tx
-		savepoint int
-		err       error
+		tx            *sql.Tx
+		state         = adapter.txState
+		savepointName string
+		err           error
 	)
 
+	start := time.Now()
 	if adapter.Tx != nil {
 		tx = adapter.Tx
-		savepoint = adapter.savepoint + 1
-		_, _, err = adapter.Exec("SAVEPOINT s"+strconv.Itoa(savepoint)+";", []interface{}{})
+		savepointName = state.nextSavepoint()
+		_, _, err = adapter.ExecContext(ctx, "SAVEPOINT "+savepointName+";", []interface{}{})
 	} else {
-		tx, err = adapter.DB.Begin()
+		tx, err = adapter.DB.BeginTx(ctx, opts)
+		state = &txState{}
+		adapter.log(LogEntry{Duration: time.Since(start), Err: err, Operation: OpBegin})
 	}
 
 	return &Adapter{
-		Config:    adapter.Config,
-		Tx:        tx,
-		savepoint: savepoint,
+		Config:        adapter.Config,
+		Tx:            tx,
+		txState:       state,
+		savepointName: savepointName,
 	}, err
 }
 
 // Commit commits current transaction.
 func (adapter *Adapter) Commit() error {
-	var err error
+	return adapter.CommitContext(context.Background())
+}
 
+// CommitContext commits current transaction, honoring ctx cancellation
+// and deadlines for the savepoint release statement it may issue.
+// Calling it a second time on the same transaction or savepoint returns
+// ErrTxClosed.
+func (adapter *Adapter) CommitContext(ctx context.Context) error {
 	if adapter.Tx == nil {
-		err = errors.New("unable to commit outside transaction")
-	} else if adapter.savepoint > 0 {
-		_, _, err = adapter.Exec("RELEASE SAVEPOINT s"+strconv.Itoa(adapter.savepoint)+";", []interface{}{})
+		return adapter.Config.ErrorFunc(errors.New("unable to commit outside transaction"))
+	}
+
+	if err := adapter.closeStatus(txCommitted); err != nil {
+		return adapter.Config.ErrorFunc(err)
+	}
+
+	var err error
+	if adapter.savepointName != "" {
+		_, _, err = adapter.ExecContext(ctx, "RELEASE SAVEPOINT "+adapter.savepointName+";", []interface{}{})
 	} else {
+		start := time.Now()
 		err = adapter.Tx.Commit()
+		adapter.log(LogEntry{Duration: time.Since(start), Err: err, Operation: OpCommit})
 	}
 
 	return adapter.Config.ErrorFunc(err)
@@ -195,24 +361,58 @@ func (adapter *Adapter) Commit() error {
 
 // Rollback revert current transaction.
 func (adapter *Adapter) Rollback() error {
-	var err error
+	return adapter.RollbackContext(context.Background())
+}
 
+// RollbackContext revert current transaction, honoring ctx cancellation
+// and deadlines for the savepoint rollback statement it may issue.
+// Calling it a second time on the same transaction or savepoint returns
+// ErrTxClosed.
+func (adapter *Adapter) RollbackContext(ctx context.Context) error {
 	if adapter.Tx == nil {
-		err = errors.New("unable to rollback outside transaction")
-	} else if adapter.savepoint > 0 {
-		_, _, err = adapter.Exec("ROLLBACK TO SAVEPOINT s"+strconv.Itoa(adapter.savepoint)+";", []interface{}{})
+		return adapter.Config.ErrorFunc(errors.New("unable to rollback outside transaction"))
+	}
+
+	if err := adapter.closeStatus(txRolledBack); err != nil {
+		return adapter.Config.ErrorFunc(err)
+	}
+
+	var err error
+	if adapter.savepointName != "" {
+		_, _, err = adapter.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+adapter.savepointName+";", []interface{}{})
 	} else {
+		start := time.Now()
 		err = adapter.Tx.Rollback()
+		adapter.log(LogEntry{Duration: time.Since(start), Err: err, Operation: OpRollback})
 	}
 
 	return adapter.Config.ErrorFunc(err)
 }
 
-// New initialize adapter without db.
+// closeStatus transitions adapter's status from open to to, returning
+// ErrTxClosed if it was already committed or rolled back.
+func (adapter *Adapter) closeStatus(to txStatus) error {
+	adapter.statusMu.Lock()
+	defer adapter.statusMu.Unlock()
+
+	if adapter.status != txOpen {
+		return ErrTxClosed
+	}
+
+	adapter.status = to
+	return nil
+}
+
+// New initialize adapter without db. Pool tuning
+// (Config.MaxOpenConns/MaxIdleConns/ConnMaxLifetime/ConnMaxIdleTime) and
+// the heartbeat (Config.OnDisconnect) are only applied by Open; callers
+// wiring their own *sql.DB can start the heartbeat by calling
+// StartHeartbeat once DB is set, but the pool tuning is not applied to
+// that DB.
 func New(config *Config) *Adapter {
 	adapter := &Adapter{
 		Config: config,
 	}
 
 	return adapter
-}
\ No newline at end of file
+}
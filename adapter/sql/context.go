@@ -0,0 +1,44 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Fs02/grimoire"
+)
+
+// ContextAdapter is implemented by Adapter in addition to
+// grimoire.Adapter.
+//
+// Known limitation: the grimoire core package (grimoire.Repo,
+// repo.FindContext) doesn't ship in this tree, so it can't be changed
+// here to route through the *Context methods below. As of this chunk,
+// repo.FindContext(ctx, ...) does NOT honor ctx - nothing in grimoire.Repo
+// performs the type assertion ContextAdapter exists to enable. This
+// interface only documents the contract a future change to
+// grimoire.Repo would type-assert against to reach these methods:
+//
+//	if ctxAdapter, ok := adapter.(sql.ContextAdapter); ok {
+//		cursor, err = ctxAdapter.QueryContext(ctx, query, loggers...)
+//	} else {
+//		cursor, err = adapter.Query(query, loggers...)
+//	}
+//
+// Until grimoire.Repo is changed to do that, the *Context methods are
+// only reachable by calling them directly on a concrete *sql.Adapter.
+type ContextAdapter interface {
+	grimoire.Adapter
+
+	AggregateContext(ctx context.Context, query grimoire.Query, mode string, field string, loggers ...grimoire.Logger) (int, error)
+	QueryContext(ctx context.Context, query grimoire.Query, loggers ...grimoire.Logger) (grimoire.Cursor, error)
+	ExecContext(ctx context.Context, statement string, args []interface{}, loggers ...grimoire.Logger) (int64, int64, error)
+	InsertContext(ctx context.Context, query grimoire.Query, changes grimoire.Changes, loggers ...grimoire.Logger) (interface{}, error)
+	InsertAllContext(ctx context.Context, query grimoire.Query, fields []string, allchanges []grimoire.Changes, loggers ...grimoire.Logger) ([]interface{}, error)
+	UpdateContext(ctx context.Context, query grimoire.Query, changes grimoire.Changes, loggers ...grimoire.Logger) error
+	DeleteContext(ctx context.Context, query grimoire.Query, loggers ...grimoire.Logger) error
+	BeginContext(ctx context.Context, opts *sql.TxOptions) (grimoire.Adapter, error)
+	CommitContext(ctx context.Context) error
+	RollbackContext(ctx context.Context) error
+}
+
+var _ ContextAdapter = (*Adapter)(nil)
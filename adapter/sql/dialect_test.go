@@ -0,0 +1,112 @@
+package sql_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Fs02/grimoire/adapter/mysql"
+	"github.com/Fs02/grimoire/adapter/postgres"
+	sqlDriver "github.com/Fs02/grimoire/adapter/sql"
+	"github.com/Fs02/grimoire/adapter/sql/sqltest"
+	"github.com/Fs02/grimoire/adapter/sqlite3"
+)
+
+// dialects covers the three built-in ColumnMapper/DropIndexStatement
+// combinations, so mysql/postgres/sqlite3 share one Transaction-wiring
+// test instead of three copy-pasted fixtures that differ only by
+// package name.
+var dialects = []struct {
+	name               string
+	config             *sqlDriver.Config
+	columnMapper       sqlDriver.ColumnMapper
+	dropIndexStatement func(table, name string) string
+}{
+	{
+		name: "mysql",
+		config: &sqlDriver.Config{
+			Placeholder: "?",
+			EscapeChar:  "`",
+		},
+		columnMapper:       mysql.ColumnMapper,
+		dropIndexStatement: mysql.DropIndexStatement,
+	},
+	{
+		name: "postgres",
+		config: &sqlDriver.Config{
+			Placeholder: "$",
+			Ordinal:     true,
+			EscapeChar:  `"`,
+		},
+		columnMapper: postgres.ColumnMapper,
+	},
+	{
+		name: "sqlite3",
+		config: &sqlDriver.Config{
+			Placeholder: "?",
+			EscapeChar:  `"`,
+		},
+		columnMapper: sqlite3.ColumnMapper,
+	},
+}
+
+func newDialectAdapter(t *testing.T, d int) *sqlDriver.Adapter {
+	t.Helper()
+
+	db := sqltest.Open()
+	t.Cleanup(func() { db.Close() })
+
+	config := *dialects[d].config
+	config.ErrorFunc = func(err error) error { return err }
+	config.ColumnMapper = dialects[d].columnMapper
+	config.DropIndexStatement = dialects[d].dropIndexStatement
+
+	return &sqlDriver.Adapter{DB: db, Config: &config}
+}
+
+func TestTransactionWrapsMultiStatementMigration(t *testing.T) {
+	for d, dialect := range dialects {
+		d := d
+		t.Run(dialect.name, func(t *testing.T) {
+			adapter := newDialectAdapter(t, d)
+
+			err := adapter.Transaction(func(tx *sqlDriver.Adapter) error {
+				if err := tx.CreateTable("users", []sqlDriver.ColumnDefinition{
+					{Name: "id", Type: sqlDriver.Int, PrimaryKey: true, AutoIncrement: true},
+					{Name: "email", Type: sqlDriver.String},
+				}, sqlDriver.Index("idx_users_email", true, "email")); err != nil {
+					return err
+				}
+
+				return tx.DropIndex("users", "idx_users_email")
+			})
+
+			if err != nil {
+				t.Fatalf("Transaction() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestTransactionRollsBackFailedMigration(t *testing.T) {
+	for d, dialect := range dialects {
+		d := d
+		t.Run(dialect.name, func(t *testing.T) {
+			adapter := newDialectAdapter(t, d)
+
+			wantErr := errors.New("boom")
+			err := adapter.Transaction(func(tx *sqlDriver.Adapter) error {
+				if err := tx.CreateTable("users", []sqlDriver.ColumnDefinition{
+					{Name: "id", Type: sqlDriver.Int, PrimaryKey: true, AutoIncrement: true},
+				}); err != nil {
+					return err
+				}
+
+				return wantErr
+			})
+
+			if !errors.Is(err, wantErr) {
+				t.Fatalf("Transaction() = %v, want %v", err, wantErr)
+			}
+		})
+	}
+}
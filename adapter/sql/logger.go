@@ -0,0 +1,93 @@
+package sql
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Operation identifies which Adapter method produced a LogEntry.
+type Operation string
+
+// Supported operations.
+const (
+	OpQuery    Operation = "query"
+	OpExec     Operation = "exec"
+	OpBegin    Operation = "begin"
+	OpCommit   Operation = "commit"
+	OpRollback Operation = "rollback"
+)
+
+// LogLevel classifies how noteworthy a LogEntry is, letting Logger
+// implementations route it accordingly (e.g. stdlib log prefix, zap
+// level, zerolog level).
+type LogLevel int
+
+// Supported levels. A LogEntry is LevelWarn when it errored or ran at
+// or above Config.SlowThreshold; everything else is LevelDebug and is
+// subject to Config.LogSampleRate.
+const (
+	LevelDebug LogLevel = iota
+	LevelWarn
+)
+
+// LogEntry describes a single statement execution, passed to
+// Logger.Log.
+type LogEntry struct {
+	Statement    string
+	Args         []interface{}
+	Duration     time.Duration
+	Err          error
+	Operation    Operation
+	Collection   string
+	RowsAffected int64
+	LastInsertID int64
+	Level        LogLevel
+}
+
+// Logger receives a LogEntry for the statements the Adapter runs through
+// Query/Exec/Aggregate and the transaction methods, subject to
+// Config.LogSampleRate for LevelDebug entries; the bookkeeping queries
+// Migrator issues via rawQuery are not logged. Log is always called
+// synchronously from within Query/Exec/Aggregate and
+// the transaction methods, so that ordering and Args are never raced;
+// implementations that want asynchronous dispatch (to avoid blocking
+// the caller) must do so themselves. Built-in implementations wrapping
+// the stdlib logger, zap and zerolog live in the logger sub-package.
+type Logger interface {
+	Log(LogEntry)
+}
+
+// log dispatches entry to Config.Logger, applying SlowThreshold and
+// LogSampleRate. Errored or slow (>= SlowThreshold) entries are always
+// logged at LevelWarn; everything else is LevelDebug and sampled at a
+// rate of 1/LogSampleRate (a rate of 0 or 1 logs every entry).
+func (adapter *Adapter) log(entry LogEntry) {
+	if adapter.Config.Logger == nil {
+		return
+	}
+
+	if entry.Err != nil {
+		entry.Level = LevelWarn
+		adapter.Config.Logger.Log(entry)
+		return
+	}
+
+	if adapter.Config.SlowThreshold > 0 && entry.Duration >= adapter.Config.SlowThreshold {
+		entry.Level = LevelWarn
+		adapter.Config.Logger.Log(entry)
+		return
+	}
+
+	entry.Level = LevelDebug
+
+	rate := adapter.Config.LogSampleRate
+	if rate <= 1 {
+		adapter.Config.Logger.Log(entry)
+		return
+	}
+
+	tick := atomic.AddInt64(&adapter.sampleTick, 1)
+	if tick%int64(rate) == 0 {
+		adapter.Config.Logger.Log(entry)
+	}
+}
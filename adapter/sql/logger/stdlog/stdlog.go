@@ -0,0 +1,43 @@
+// Package stdlog adapts the standard library's log.Logger to
+// sql.Logger, for use as sql.Config.Logger.
+package stdlog
+
+import (
+	"log"
+
+	"github.com/Fs02/grimoire/adapter/sql"
+)
+
+// Logger wraps a *log.Logger, printing WARN-level entries (errors and
+// slow statements) with a "WARN" prefix and everything else with a
+// "DEBUG" prefix.
+type Logger struct {
+	*log.Logger
+}
+
+var _ sql.Logger = (*Logger)(nil)
+
+// New wraps logger as a sql.Logger. A nil logger falls back to
+// log.Default().
+func New(logger *log.Logger) *Logger {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return &Logger{Logger: logger}
+}
+
+// Log implements sql.Logger.
+func (l *Logger) Log(entry sql.LogEntry) {
+	level := "DEBUG"
+	if entry.Level == sql.LevelWarn {
+		level = "WARN"
+	}
+
+	if entry.Err != nil {
+		l.Printf("[%s] %s %s | args=%v duration=%s error=%s", level, entry.Operation, entry.Statement, entry.Args, entry.Duration, entry.Err)
+		return
+	}
+
+	l.Printf("[%s] %s %s | args=%v duration=%s", level, entry.Operation, entry.Statement, entry.Args, entry.Duration)
+}
@@ -0,0 +1,47 @@
+// Package zap adapts a *zap.Logger to sql.Logger, for use as
+// sql.Config.Logger.
+package zap
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/Fs02/grimoire/adapter/sql"
+)
+
+// Logger wraps a *zap.Logger.
+type Logger struct {
+	log *zap.Logger
+}
+
+var _ sql.Logger = (*Logger)(nil)
+
+// New wraps log as a sql.Logger.
+func New(log *zap.Logger) *Logger {
+	return &Logger{log: log}
+}
+
+// Log implements sql.Logger, logging at Warn for errors and slow
+// statements (sql.LevelWarn) and at Debug otherwise.
+func (l *Logger) Log(entry sql.LogEntry) {
+	fields := []zap.Field{
+		zap.String("operation", string(entry.Operation)),
+		zap.String("statement", entry.Statement),
+		zap.Any("args", entry.Args),
+		zap.Duration("duration", entry.Duration),
+		zap.String("collection", entry.Collection),
+		zap.Int64("rows_affected", entry.RowsAffected),
+		zap.Int64("last_insert_id", entry.LastInsertID),
+	}
+
+	if entry.Err != nil {
+		l.log.Warn("sql", append(fields, zap.Error(entry.Err))...)
+		return
+	}
+
+	if entry.Level == sql.LevelWarn {
+		l.log.Warn("sql", fields...)
+		return
+	}
+
+	l.log.Debug("sql", fields...)
+}
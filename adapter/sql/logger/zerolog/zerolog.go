@@ -0,0 +1,45 @@
+// Package zerolog adapts a zerolog.Logger to sql.Logger, for use as
+// sql.Config.Logger.
+package zerolog
+
+import (
+	"github.com/rs/zerolog"
+
+	"github.com/Fs02/grimoire/adapter/sql"
+)
+
+// Logger wraps a zerolog.Logger.
+type Logger struct {
+	log zerolog.Logger
+}
+
+var _ sql.Logger = (*Logger)(nil)
+
+// New wraps log as a sql.Logger.
+func New(log zerolog.Logger) *Logger {
+	return &Logger{log: log}
+}
+
+// Log implements sql.Logger, logging at Warn for errors and slow
+// statements (sql.LevelWarn) and at Debug otherwise.
+func (l *Logger) Log(entry sql.LogEntry) {
+	level := zerolog.DebugLevel
+	if entry.Level == sql.LevelWarn {
+		level = zerolog.WarnLevel
+	}
+
+	event := l.log.WithLevel(level).
+		Str("operation", string(entry.Operation)).
+		Str("statement", entry.Statement).
+		Interface("args", entry.Args).
+		Dur("duration", entry.Duration).
+		Str("collection", entry.Collection).
+		Int64("rows_affected", entry.RowsAffected).
+		Int64("last_insert_id", entry.LastInsertID)
+
+	if entry.Err != nil {
+		event = event.Err(entry.Err)
+	}
+
+	event.Msg("sql")
+}
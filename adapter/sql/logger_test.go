@@ -0,0 +1,46 @@
+package sql
+
+import (
+	"sync"
+	"testing"
+)
+
+type countingLogger struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (l *countingLogger) Log(LogEntry) {
+	l.mu.Lock()
+	l.count++
+	l.mu.Unlock()
+}
+
+func TestAdapterLogSampleTickIsRaceFree(t *testing.T) {
+	logger := &countingLogger{}
+	adapter := &Adapter{
+		Config: &Config{
+			Logger:        logger,
+			LogSampleRate: 10,
+		},
+	}
+
+	const calls = 1000
+
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			adapter.log(LogEntry{Operation: OpQuery})
+		}()
+	}
+	wg.Wait()
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+
+	if want := calls / 10; logger.count != want {
+		t.Fatalf("logged %d entries out of %d calls at a 1/10 sample rate, want exactly %d (lost increments indicate an unguarded sampleTick)", logger.count, calls, want)
+	}
+}
@@ -0,0 +1,313 @@
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrDirty is returned by Migrator operations when the last migration
+// failed midway and left the schema_migrations table marked dirty.
+var ErrDirty = errors.New("sql: database is dirty, fix manually and clear the dirty flag")
+
+// Migration is a single versioned schema change, registered via
+// Migrator.Register. Up and Down receive a Schema bound to the adapter
+// running the migration, should call its DDL methods (CreateTable,
+// AlterTable, ...) and return the first error one of them reports so
+// apply can roll back and leave the database marked dirty instead of
+// silently advancing the version.
+type Migration struct {
+	ID   int64
+	Up   func(*Schema) error
+	Down func(*Schema) error
+}
+
+// Migrator tracks and applies Migrations against an Adapter. It keeps a
+// single-row schema_migrations table holding the id of the last applied
+// migration and whether the database was left dirty by a failed run.
+type Migrator struct {
+	adapter    *Adapter
+	migrations []Migration
+}
+
+// NewMigrator creates a Migrator bound to adapter.
+func NewMigrator(adapter *Adapter) *Migrator {
+	return &Migrator{adapter: adapter}
+}
+
+// Register adds a migration identified by id (conventionally a
+// timestamp, e.g. 20210102150405). Migrations are applied in ascending
+// id order regardless of registration order.
+func (m *Migrator) Register(id int64, up, down func(*Schema) error) {
+	m.migrations = append(m.migrations, Migration{ID: id, Up: up, Down: down})
+}
+
+func (m *Migrator) sorted() []Migration {
+	sorted := make([]Migration, len(m.migrations))
+	copy(sorted, m.migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+func (m *Migrator) ensureVersionTable() error {
+	adapter := m.adapter
+
+	rows, err := adapter.rawQuery("SELECT id FROM " + adapter.quote("schema_migrations") + " LIMIT 1;")
+	if err == nil {
+		rows.Close()
+		return nil
+	}
+
+	if err := adapter.CreateTable("schema_migrations", []ColumnDefinition{
+		{Name: "id", Type: BigInt, NotNull: true},
+		{Name: "dirty", Type: Bool, NotNull: true, Default: false},
+	}); err != nil {
+		return err
+	}
+
+	_, _, err = adapter.Exec("INSERT INTO "+adapter.quote("schema_migrations")+" (id, dirty) VALUES (0, false);", nil)
+	return err
+}
+
+// currentVersion returns the id of the last applied migration and
+// ErrDirty if the row is marked dirty.
+func (m *Migrator) currentVersion() (int64, error) {
+	rows, err := m.adapter.rawQuery("SELECT id, dirty FROM " + m.adapter.quote("schema_migrations") + " LIMIT 1;")
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var (
+		id    int64
+		dirty bool
+	)
+
+	if rows.Next() {
+		if err := rows.Scan(&id, &dirty); err != nil {
+			return 0, err
+		}
+	}
+
+	if dirty {
+		return id, ErrDirty
+	}
+
+	return id, nil
+}
+
+// Status returns the id of the last applied migration.
+func (m *Migrator) Status() (int64, error) {
+	if err := m.ensureVersionTable(); err != nil {
+		return 0, err
+	}
+
+	return m.currentVersion()
+}
+
+// Up applies all migrations that have not yet been applied.
+func (m *Migrator) Up() error {
+	if err := m.ensureVersionTable(); err != nil {
+		return err
+	}
+
+	current, err := m.currentVersion()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range m.sorted() {
+		if migration.ID <= current {
+			continue
+		}
+		if err := m.apply(migration, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Down rolls back every applied migration.
+func (m *Migrator) Down() error {
+	if err := m.ensureVersionTable(); err != nil {
+		return err
+	}
+
+	current, err := m.currentVersion()
+	if err != nil {
+		return err
+	}
+
+	migrations := m.sorted()
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		if migration.ID > current {
+			continue
+		}
+		if err := m.apply(migration, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Redo rolls back and re-applies the most recent migration.
+func (m *Migrator) Redo() error {
+	if err := m.Step(-1); err != nil {
+		return err
+	}
+
+	return m.Step(1)
+}
+
+// Step applies (n > 0) or rolls back (n < 0) n migrations relative to
+// the current version.
+func (m *Migrator) Step(n int) error {
+	if err := m.ensureVersionTable(); err != nil {
+		return err
+	}
+
+	current, err := m.currentVersion()
+	if err != nil {
+		return err
+	}
+
+	migrations := m.sorted()
+
+	if n > 0 {
+		applied := 0
+		for _, migration := range migrations {
+			if applied >= n {
+				break
+			}
+			if migration.ID <= current {
+				continue
+			}
+			if err := m.apply(migration, true); err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	}
+
+	reverted := 0
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if reverted >= -n {
+			break
+		}
+		migration := migrations[i]
+		if migration.ID > current {
+			continue
+		}
+		if err := m.apply(migration, false); err != nil {
+			return err
+		}
+		reverted++
+	}
+
+	return nil
+}
+
+// apply runs migration's Up or Down function inside a transaction,
+// marking the schema_migrations row dirty for the duration so a crash
+// mid-migration is detectable on the next run. The dirty marker is
+// committed on its own, ahead of the migration's transaction, since a
+// marker living inside the same transaction it's meant to survive would
+// be undone by the very Rollback that's supposed to leave it behind. It
+// rolls back and returns the migration's error if Up/Down reports one,
+// and rolls back and re-panics if Up/Down panics, so neither failure
+// mode can leave the transaction open or let dirty=false/the version
+// bump go through as if the migration had succeeded.
+func (m *Migrator) apply(migration Migration, up bool) (err error) {
+	if _, _, err = m.adapter.Exec("UPDATE "+m.adapter.quote("schema_migrations")+" SET dirty = true;", nil); err != nil {
+		return err
+	}
+
+	txAdapter, err := m.beginTx()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			txAdapter.Rollback()
+			panic(p)
+		}
+	}()
+
+	schema := &Schema{adapter: txAdapter}
+
+	var migrateErr error
+	if up {
+		migrateErr = migration.Up(schema)
+	} else {
+		migrateErr = migration.Down(schema)
+	}
+
+	if migrateErr != nil {
+		txAdapter.Rollback()
+		if up {
+			return fmt.Errorf("sql: failed to apply migration %d: %w", migration.ID, migrateErr)
+		}
+		return fmt.Errorf("sql: failed to revert migration %d: %w", migration.ID, migrateErr)
+	}
+
+	version := migration.ID
+	if !up {
+		version = m.previousVersion(migration.ID)
+	}
+
+	// version is produced internally (the migration's own ID, or the
+	// previous one on Down), never user input, so interpolating it
+	// avoids hardcoding a "?" bind placeholder that only some dialects
+	// (Config.Placeholder/Ordinal) accept.
+	statement := fmt.Sprintf("UPDATE %s SET id = %d, dirty = false;", txAdapter.quote("schema_migrations"), version)
+	if _, _, err = txAdapter.Exec(statement, nil); err != nil {
+		txAdapter.Rollback()
+		if up {
+			return fmt.Errorf("sql: failed to apply migration %d: %w", migration.ID, err)
+		}
+		return fmt.Errorf("sql: failed to revert migration %d: %w", migration.ID, err)
+	}
+
+	return txAdapter.Commit()
+}
+
+// previousVersion returns the id of the migration immediately preceding
+// id, or 0 if id is the oldest registered migration.
+func (m *Migrator) previousVersion(id int64) int64 {
+	var previous int64
+
+	for _, migration := range m.sorted() {
+		if migration.ID >= id {
+			break
+		}
+		previous = migration.ID
+	}
+
+	return previous
+}
+
+func (m *Migrator) beginTx() (*Adapter, error) {
+	tx, err := m.adapter.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.(*Adapter), nil
+}
+
+// rawQuery runs statement directly against the adapter's current
+// connection (transaction, if any), bypassing the Builder since DDL and
+// migration bookkeeping don't map to a grimoire.Query.
+func (adapter *Adapter) rawQuery(statement string) (*sql.Rows, error) {
+	if adapter.Tx != nil {
+		return adapter.Tx.Query(statement)
+	}
+
+	return adapter.DB.Query(statement)
+}
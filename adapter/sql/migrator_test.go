@@ -0,0 +1,128 @@
+package sql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Fs02/grimoire/adapter/sql/sqltest"
+)
+
+func newTestAdapter(t *testing.T) *Adapter {
+	t.Helper()
+
+	db := sqltest.Open()
+	t.Cleanup(func() { db.Close() })
+
+	return &Adapter{
+		DB: db,
+		Config: &Config{
+			Placeholder: "?",
+			EscapeChar:  "`",
+			ErrorFunc:   func(err error) error { return err },
+		},
+	}
+}
+
+func TestMigratorApplyPropagatesError(t *testing.T) {
+	adapter := newTestAdapter(t)
+	migrator := NewMigrator(adapter)
+
+	wantErr := errors.New("boom")
+	migrator.Register(1,
+		func(s *Schema) error { return wantErr },
+		func(s *Schema) error { return nil },
+	)
+
+	if err := migrator.Up(); !errors.Is(err, wantErr) {
+		t.Fatalf("Up() = %v, want wrapped %v", err, wantErr)
+	}
+
+	// apply must have rolled back before it could mark the migration
+	// clean, leaving the database dirty instead of silently advancing.
+	if _, err := migrator.Status(); !errors.Is(err, ErrDirty) {
+		t.Fatalf("Status() = %v, want %v", err, ErrDirty)
+	}
+}
+
+func TestMigratorApplyAdvancesVersionOnSuccess(t *testing.T) {
+	adapter := newTestAdapter(t)
+	migrator := NewMigrator(adapter)
+
+	migrator.Register(1,
+		func(s *Schema) error { return nil },
+		func(s *Schema) error { return nil },
+	)
+
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up() = %v, want nil", err)
+	}
+
+	version, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Status() = %v, want nil", err)
+	}
+	if version != 1 {
+		t.Fatalf("Status() version = %d, want 1", version)
+	}
+}
+
+// TestMigratorApplyWorksWithPostgresStylePlaceholders guards against
+// apply() hardcoding a "?" bind placeholder for the final version/dirty
+// update, which is a syntax error against a Config shaped like the
+// postgres adapter's ($1-style, ordinal placeholders).
+func TestMigratorApplyWorksWithPostgresStylePlaceholders(t *testing.T) {
+	adapter := newTestAdapter(t)
+	adapter.Config.Placeholder = "$"
+	adapter.Config.Ordinal = true
+	adapter.Config.EscapeChar = `"`
+	logger := &statementLogger{}
+	adapter.Config.Logger = logger
+
+	migrator := NewMigrator(adapter)
+	migrator.Register(1,
+		func(s *Schema) error { return nil },
+		func(s *Schema) error { return nil },
+	)
+
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up() = %v, want nil", err)
+	}
+
+	for _, statement := range logger.statements {
+		if strings.Contains(statement, "?") {
+			t.Fatalf("Up() executed %q, which uses a \"?\" placeholder", statement)
+		}
+	}
+
+	version, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Status() = %v, want nil", err)
+	}
+	if version != 1 {
+		t.Fatalf("Status() version = %d, want 1", version)
+	}
+}
+
+func TestMigratorApplyRecoversPanic(t *testing.T) {
+	adapter := newTestAdapter(t)
+	migrator := NewMigrator(adapter)
+
+	migrator.Register(1,
+		func(s *Schema) error { panic("boom") },
+		func(s *Schema) error { return nil },
+	)
+
+	defer func() {
+		p := recover()
+		if p != "boom" {
+			t.Fatalf("recover() = %v, want %q to propagate out of Up()", p, "boom")
+		}
+
+		if _, err := migrator.Status(); !errors.Is(err, ErrDirty) {
+			t.Fatalf("Status() after panicking migration = %v, want %v", err, ErrDirty)
+		}
+	}()
+
+	migrator.Up()
+}
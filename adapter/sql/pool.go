@@ -0,0 +1,136 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// defaultHeartbeatInterval is used when Config.OnDisconnect is set but
+// Config.HeartbeatInterval is left at its zero value.
+const defaultHeartbeatInterval = 30 * time.Second
+
+// ErrDisconnected is passed to Config.OnDisconnect when the heartbeat's
+// PingContext first fails.
+var ErrDisconnected = errors.New("sql: lost connection to database")
+
+// Open opens db with database/sql's Open using driverName and dsn,
+// applies config's pool tuning, wraps the result in an Adapter and, if
+// config.OnDisconnect is set, starts the background heartbeat.
+func Open(driverName, dsn string, config *Config) (*Adapter, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	adapter := New(config)
+	adapter.DB = db
+	adapter.applyPoolConfig()
+
+	if config.OnDisconnect != nil {
+		adapter.StartHeartbeat()
+	}
+
+	return adapter, nil
+}
+
+// applyPoolConfig applies Config.MaxOpenConns/MaxIdleConns/
+// ConnMaxLifetime/ConnMaxIdleTime to adapter.DB. Zero values leave the
+// corresponding database/sql default in place.
+func (adapter *Adapter) applyPoolConfig() {
+	config := adapter.Config
+
+	if config.MaxOpenConns > 0 {
+		adapter.DB.SetMaxOpenConns(config.MaxOpenConns)
+	}
+
+	if config.MaxIdleConns > 0 {
+		adapter.DB.SetMaxIdleConns(config.MaxIdleConns)
+	}
+
+	if config.ConnMaxLifetime > 0 {
+		adapter.DB.SetConnMaxLifetime(config.ConnMaxLifetime)
+	}
+
+	if config.ConnMaxIdleTime > 0 {
+		adapter.DB.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+	}
+}
+
+// StartHeartbeat runs a background PingContext against adapter.DB every
+// Config.HeartbeatInterval (default defaultHeartbeatInterval), calling
+// Config.OnDisconnect once when a ping fails and retrying with
+// exponential backoff (capped at the heartbeat interval) until the
+// connection recovers. It is started automatically by Open when
+// Config.OnDisconnect is set; callers that assign adapter.DB directly
+// may call it themselves. Close stops it.
+func (adapter *Adapter) StartHeartbeat() {
+	if adapter.heartbeatDone != nil {
+		return
+	}
+
+	interval := adapter.Config.HeartbeatInterval
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+
+	adapter.heartbeatDone = make(chan struct{})
+	go adapter.heartbeat(interval, adapter.heartbeatDone)
+}
+
+func (adapter *Adapter) heartbeat(interval time.Duration, done chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := adapter.ping(); err != nil {
+				adapter.reconnect(interval, done)
+			}
+		}
+	}
+}
+
+// reconnect keeps probing adapter.DB with exponential backoff (starting
+// at one tenth of interval, capped at interval) until a ping succeeds or
+// done is closed, invoking Config.OnDisconnect once for the initial
+// failure.
+func (adapter *Adapter) reconnect(interval time.Duration, done chan struct{}) {
+	if adapter.Config.OnDisconnect != nil {
+		adapter.Config.OnDisconnect(ErrDisconnected)
+	}
+
+	backoff := interval / 10
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-time.After(backoff):
+			if err := adapter.ping(); err == nil {
+				return
+			}
+
+			if backoff < interval {
+				backoff *= 2
+				if backoff > interval {
+					backoff = interval
+				}
+			}
+		}
+	}
+}
+
+func (adapter *Adapter) ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return adapter.DB.PingContext(ctx)
+}
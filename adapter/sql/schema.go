@@ -0,0 +1,278 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ColumnType represents the logical type of a column, mapped to a
+// dialect-specific SQL type by each adapter sub-package.
+type ColumnType string
+
+// Supported column types.
+const (
+	Int      ColumnType = "int"
+	BigInt   ColumnType = "bigint"
+	String   ColumnType = "string"
+	Text     ColumnType = "text"
+	Bool     ColumnType = "bool"
+	DateTime ColumnType = "datetime"
+	Decimal  ColumnType = "decimal"
+	JSON     ColumnType = "json"
+)
+
+// ColumnDefinition defines a single column of a table.
+type ColumnDefinition struct {
+	Name          string
+	Type          ColumnType
+	Limit         int
+	PrimaryKey    bool
+	AutoIncrement bool
+	Unique        bool
+	NotNull       bool
+	Default       interface{}
+	Foreign       *ForeignKeyDefinition
+}
+
+// ForeignKeyDefinition describes a foreign key constraint attached to a
+// column.
+type ForeignKeyDefinition struct {
+	Table    string
+	Column   string
+	OnDelete string
+	OnUpdate string
+}
+
+// IndexDefinition describes an index created via AddIndex.
+type IndexDefinition struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// TableOption configures a CreateTable/AlterTable call.
+type TableOption func(*tableOptions)
+
+type tableOptions struct {
+	indexes []IndexDefinition
+}
+
+// Index adds an index to the table being created or altered.
+func Index(name string, unique bool, columns ...string) TableOption {
+	return func(o *tableOptions) {
+		o.indexes = append(o.indexes, IndexDefinition{
+			Name:    name,
+			Columns: columns,
+			Unique:  unique,
+		})
+	}
+}
+
+// ColumnMapper maps a ColumnDefinition to its dialect-specific SQL type
+// and auto-increment clause. Each adapter sub-package (postgres, mysql,
+// sqlite3) supplies its own via Config.ColumnMapper, since dialects
+// disagree on how auto-increment is expressed (e.g. MySQL appends
+// AUTO_INCREMENT, postgres swaps the type for serial/bigserial).
+type ColumnMapper func(def ColumnDefinition) (sqlType string, autoIncrementClause string)
+
+// Schema wraps an Adapter and exposes the DDL methods used by
+// migrations. It is the value passed to Migration Up/Down functions.
+type Schema struct {
+	adapter *Adapter
+}
+
+// CreateTable builds and executes a CREATE TABLE statement for defs,
+// applying any indexes requested via opts after creation.
+func (s *Schema) CreateTable(name string, defs []ColumnDefinition, opts ...TableOption) error {
+	return s.adapter.CreateTable(name, defs, opts...)
+}
+
+// AlterTable adds the given columns to an existing table.
+func (s *Schema) AlterTable(name string, defs []ColumnDefinition, opts ...TableOption) error {
+	return s.adapter.AlterTable(name, defs, opts...)
+}
+
+// DropTable drops a table.
+func (s *Schema) DropTable(name string) error {
+	return s.adapter.DropTable(name)
+}
+
+// RenameTable renames a table.
+func (s *Schema) RenameTable(oldName, newName string) error {
+	return s.adapter.RenameTable(oldName, newName)
+}
+
+// AddIndex creates an index on table.
+func (s *Schema) AddIndex(table string, index IndexDefinition) error {
+	return s.adapter.AddIndex(table, index)
+}
+
+// DropIndex drops an index by name.
+func (s *Schema) DropIndex(table, name string) error {
+	return s.adapter.DropIndex(table, name)
+}
+
+// CreateTable builds and executes a CREATE TABLE statement for defs,
+// applying any indexes requested via opts after creation.
+func (adapter *Adapter) CreateTable(name string, defs []ColumnDefinition, opts ...TableOption) error {
+	options := adapter.buildTableOptions(opts)
+
+	var columns []string
+	for _, def := range defs {
+		columns = append(columns, adapter.columnSQL(def))
+	}
+
+	statement := fmt.Sprintf("CREATE TABLE %s (%s);", adapter.quote(name), strings.Join(columns, ", "))
+	if _, _, err := adapter.Exec(statement, nil); err != nil {
+		return err
+	}
+
+	for _, index := range options.indexes {
+		if err := adapter.AddIndex(name, index); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AlterTable adds the given columns to an existing table.
+func (adapter *Adapter) AlterTable(name string, defs []ColumnDefinition, opts ...TableOption) error {
+	options := adapter.buildTableOptions(opts)
+
+	for _, def := range defs {
+		statement := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", adapter.quote(name), adapter.columnSQL(def))
+		if _, _, err := adapter.Exec(statement, nil); err != nil {
+			return err
+		}
+	}
+
+	for _, index := range options.indexes {
+		if err := adapter.AddIndex(name, index); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DropTable drops a table.
+func (adapter *Adapter) DropTable(name string) error {
+	statement := fmt.Sprintf("DROP TABLE %s;", adapter.quote(name))
+	_, _, err := adapter.Exec(statement, nil)
+	return err
+}
+
+// RenameTable renames a table.
+func (adapter *Adapter) RenameTable(oldName, newName string) error {
+	statement := fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", adapter.quote(oldName), adapter.quote(newName))
+	_, _, err := adapter.Exec(statement, nil)
+	return err
+}
+
+// AddIndex creates an index on table.
+func (adapter *Adapter) AddIndex(table string, index IndexDefinition) error {
+	var columns []string
+	for _, column := range index.Columns {
+		columns = append(columns, adapter.quote(column))
+	}
+
+	unique := ""
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+
+	statement := fmt.Sprintf("CREATE %sINDEX %s ON %s (%s);", unique, adapter.quote(index.Name), adapter.quote(table), strings.Join(columns, ", "))
+	_, _, err := adapter.Exec(statement, nil)
+	return err
+}
+
+// DropIndex drops an index by name. Most dialects drop indexes by name
+// alone, but some (e.g. MySQL) require the owning table; Config.DropIndexStatement
+// lets a dialect sub-package override the statement shape.
+func (adapter *Adapter) DropIndex(table, name string) error {
+	var statement string
+	if adapter.Config.DropIndexStatement != nil {
+		statement = adapter.Config.DropIndexStatement(adapter.quote(table), adapter.quote(name))
+	} else {
+		statement = fmt.Sprintf("DROP INDEX %s;", adapter.quote(name))
+	}
+
+	_, _, err := adapter.Exec(statement, nil)
+	return err
+}
+
+func (adapter *Adapter) buildTableOptions(opts []TableOption) *tableOptions {
+	options := &tableOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+func (adapter *Adapter) quote(name string) string {
+	return adapter.Config.EscapeChar + name + adapter.Config.EscapeChar
+}
+
+// defaultSQL renders def.Default as a SQL literal for use in a DEFAULT
+// clause. String/Text defaults are single-quoted with embedded quotes
+// doubled, and time.Time defaults are single-quoted as
+// 'YYYY-MM-DD HH:MM:SS'; every other value is rendered with its natural
+// %v form, which is already valid SQL for the numeric/bool values the
+// other ColumnTypes expect.
+func defaultSQL(def ColumnDefinition) string {
+	if def.Type == String || def.Type == Text {
+		if s, ok := def.Default.(string); ok {
+			return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+		}
+	}
+
+	if t, ok := def.Default.(time.Time); ok {
+		return "'" + t.Format("2006-01-02 15:04:05") + "'"
+	}
+
+	return fmt.Sprintf("%v", def.Default)
+}
+
+func (adapter *Adapter) columnSQL(def ColumnDefinition) string {
+	var (
+		sqlType   string
+		autoIncrs string
+	)
+
+	if adapter.Config.ColumnMapper != nil {
+		sqlType, autoIncrs = adapter.Config.ColumnMapper(def)
+	} else {
+		sqlType = string(def.Type)
+	}
+
+	parts := []string{adapter.quote(def.Name), sqlType}
+
+	if def.PrimaryKey {
+		parts = append(parts, "PRIMARY KEY")
+	}
+	if def.AutoIncrement && autoIncrs != "" {
+		parts = append(parts, autoIncrs)
+	}
+	if def.NotNull {
+		parts = append(parts, "NOT NULL")
+	}
+	if def.Unique {
+		parts = append(parts, "UNIQUE")
+	}
+	if def.Default != nil {
+		parts = append(parts, "DEFAULT "+defaultSQL(def))
+	}
+	if def.Foreign != nil {
+		parts = append(parts, fmt.Sprintf("REFERENCES %s(%s)", adapter.quote(def.Foreign.Table), adapter.quote(def.Foreign.Column)))
+		if def.Foreign.OnDelete != "" {
+			parts = append(parts, "ON DELETE "+def.Foreign.OnDelete)
+		}
+		if def.Foreign.OnUpdate != "" {
+			parts = append(parts, "ON UPDATE "+def.Foreign.OnUpdate)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
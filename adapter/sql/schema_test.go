@@ -0,0 +1,64 @@
+package sql
+
+import "testing"
+
+func TestDefaultSQLQuotesStringDefaults(t *testing.T) {
+	got := defaultSQL(ColumnDefinition{Type: String, Default: "pending"})
+	if want := "'pending'"; got != want {
+		t.Fatalf("defaultSQL() = %q, want %q", got, want)
+	}
+}
+
+func TestDefaultSQLEscapesEmbeddedQuotes(t *testing.T) {
+	got := defaultSQL(ColumnDefinition{Type: Text, Default: "it's fine"})
+	if want := "'it''s fine'"; got != want {
+		t.Fatalf("defaultSQL() = %q, want %q", got, want)
+	}
+}
+
+func TestDefaultSQLLeavesNonStringTypesUnquoted(t *testing.T) {
+	got := defaultSQL(ColumnDefinition{Type: Int, Default: 0})
+	if want := "0"; got != want {
+		t.Fatalf("defaultSQL() = %q, want %q", got, want)
+	}
+}
+
+// statementLogger records the Statement of every LogEntry it receives.
+type statementLogger struct {
+	statements []string
+}
+
+func (l *statementLogger) Log(entry LogEntry) {
+	l.statements = append(l.statements, entry.Statement)
+}
+
+func TestDropIndexUsesDialectStatementWhenConfigured(t *testing.T) {
+	adapter := newTestAdapter(t)
+	logger := &statementLogger{}
+	adapter.Config.Logger = logger
+	adapter.Config.DropIndexStatement = func(table, name string) string {
+		return "DROP INDEX " + name + " ON " + table + ";"
+	}
+
+	if err := adapter.DropIndex("users", "idx_users_email"); err != nil {
+		t.Fatalf("DropIndex() = %v, want nil", err)
+	}
+
+	if want := "DROP INDEX `idx_users_email` ON `users`;"; len(logger.statements) != 1 || logger.statements[0] != want {
+		t.Fatalf("DropIndex() executed %q, want [%q]", logger.statements, want)
+	}
+}
+
+func TestDropIndexDefaultsToNameOnlyStatement(t *testing.T) {
+	adapter := newTestAdapter(t)
+	logger := &statementLogger{}
+	adapter.Config.Logger = logger
+
+	if err := adapter.DropIndex("users", "idx_users_email"); err != nil {
+		t.Fatalf("DropIndex() = %v, want nil", err)
+	}
+
+	if want := "DROP INDEX `idx_users_email`;"; len(logger.statements) != 1 || logger.statements[0] != want {
+		t.Fatalf("DropIndex() executed %q, want [%q]", logger.statements, want)
+	}
+}
@@ -0,0 +1,236 @@
+// Package sqltest provides a minimal in-memory database/sql driver for
+// exercising adapter/sql (and its dialect sub-packages) in tests
+// without a real database connection. It understands just enough of
+// the fixed statements sql.Migrator issues against a schema_migrations
+// table to track version/dirty state; every other statement succeeds
+// trivially, returning zero rows and reporting one row affected.
+package sqltest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+func init() {
+	sql.Register("sqltest", fakeDriver{})
+}
+
+var dsnCounter int64
+
+// Open returns a *sql.DB backed by a fresh, isolated in-memory store;
+// concurrent tests never see each other's state.
+func Open() *sql.DB {
+	dsn := strconv.FormatInt(atomic.AddInt64(&dsnCounter, 1), 10)
+
+	db, err := sql.Open("sqltest", dsn)
+	if err != nil {
+		panic(err)
+	}
+
+	return db
+}
+
+type store struct {
+	mu      sync.Mutex
+	version int64
+	dirty   bool
+}
+
+var stores sync.Map // dsn string -> *store
+
+func storeFor(dsn string) *store {
+	s, _ := stores.LoadOrStore(dsn, &store{})
+	return s.(*store)
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	return &conn{store: storeFor(dsn)}, nil
+}
+
+// conn models real transactional isolation for the one piece of state
+// that matters to Migrator tests: schema_migrations' version/dirty.
+// Statements run while inTx mutate a pending copy instead of the shared
+// store directly, so a Rollback can discard them and a Commit can
+// publish them atomically - mirroring a real driver closely enough that
+// a migration bug which relies on Rollback undoing an earlier write in
+// the same transaction actually surfaces as a test failure instead of
+// being masked by a driver that always "commits" eagerly.
+type conn struct {
+	store *store
+
+	inTx    bool
+	pending struct {
+		version int64
+		dirty   bool
+	}
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+func (c *conn) Close() error { return nil }
+
+func (c *conn) Begin() (driver.Tx, error) {
+	c.store.mu.Lock()
+	c.pending.version = c.store.version
+	c.pending.dirty = c.store.dirty
+	c.store.mu.Unlock()
+
+	c.inTx = true
+
+	return &fakeTx{conn: c}, nil
+}
+
+// Exec/Query implement the legacy (non-prepared) driver.Execer and
+// driver.Queryer, so adapter.DB.ExecContext/QueryContext work even when
+// the statement cache is disabled.
+func (c *conn) Exec(query string, args []driver.Value) (driver.Result, error) {
+	return c.exec(query, args)
+}
+
+func (c *conn) Query(query string, args []driver.Value) (driver.Rows, error) {
+	return c.query(query, args)
+}
+
+func (c *conn) exec(query string, args []driver.Value) (driver.Result, error) {
+	if c.inTx {
+		switch {
+		case strings.Contains(query, "SET dirty = true"):
+			c.pending.dirty = true
+		case strings.Contains(query, "dirty = false"):
+			c.pending.version = versionFromUpdate(query, args, c.pending.version)
+			c.pending.dirty = false
+		}
+
+		return fakeResult{}, nil
+	}
+
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	switch {
+	case strings.Contains(query, "SET dirty = true"):
+		c.store.dirty = true
+	case strings.Contains(query, "dirty = false"):
+		c.store.version = versionFromUpdate(query, args, c.store.version)
+		c.store.dirty = false
+	}
+
+	return fakeResult{}, nil
+}
+
+// versionFromUpdate extracts the migration version a "... SET id = ...,
+// dirty = false" statement is setting, whether it arrived as a bound
+// arg (a "?"/"$1" placeholder) or interpolated directly into query.
+func versionFromUpdate(query string, args []driver.Value, fallback int64) int64 {
+	if len(args) > 0 {
+		if v, ok := args[0].(int64); ok {
+			return v
+		}
+	}
+
+	if i := strings.Index(query, "SET id = "); i >= 0 {
+		var id int64
+		if _, err := fmt.Sscanf(query[i+len("SET id = "):], "%d", &id); err == nil {
+			return id
+		}
+	}
+
+	return fallback
+}
+
+func (c *conn) query(query string, _ []driver.Value) (driver.Rows, error) {
+	var version int64
+	var dirty bool
+
+	if c.inTx {
+		version, dirty = c.pending.version, c.pending.dirty
+	} else {
+		c.store.mu.Lock()
+		version, dirty = c.store.version, c.store.dirty
+		c.store.mu.Unlock()
+	}
+
+	if strings.Contains(query, "schema_migrations") {
+		cols := []string{"id"}
+		vals := []driver.Value{version}
+
+		if strings.Contains(query, "dirty") {
+			cols = append(cols, "dirty")
+			vals = append(vals, dirty)
+		}
+
+		return &rows{cols: cols, data: [][]driver.Value{vals}}, nil
+	}
+
+	return &rows{}, nil
+}
+
+type fakeTx struct {
+	conn *conn
+}
+
+func (t *fakeTx) Commit() error {
+	t.conn.store.mu.Lock()
+	t.conn.store.version = t.conn.pending.version
+	t.conn.store.dirty = t.conn.pending.dirty
+	t.conn.store.mu.Unlock()
+
+	t.conn.inTx = false
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.conn.inTx = false
+	return nil
+}
+
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+func (s *stmt) Close() error  { return nil }
+func (s *stmt) NumInput() int { return -1 }
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.exec(s.query, args)
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.query(s.query, args)
+}
+
+type fakeResult struct{}
+
+func (fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (fakeResult) RowsAffected() (int64, error) { return 1, nil }
+
+type rows struct {
+	cols []string
+	data [][]driver.Value
+	pos  int
+}
+
+func (r *rows) Columns() []string { return r.cols }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.data) {
+		return io.EOF
+	}
+
+	copy(dest, r.data[r.pos])
+	r.pos++
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package sql
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// stmtPreparer is implemented by both *sql.DB and *sql.Tx.
+type stmtPreparer interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// stmtCache is a goroutine-safe LRU cache of prepared statements, keyed
+// by their final (post-Builder) statement string. Each Adapter lazily
+// owns its own cache, bound to whichever of *sql.DB or *sql.Tx it
+// currently talks to; a *sql.Stmt prepared against the parent DB can't
+// be reused inside a transaction, so every transaction gets a fresh
+// cache prepared directly against its Tx.
+type stmtCache struct {
+	mu       sync.Mutex
+	size     int
+	entries  map[string]*list.Element
+	order    *list.List
+	preparer stmtPreparer
+}
+
+type stmtCacheEntry struct {
+	statement string
+	stmt      *sql.Stmt
+}
+
+// newStmtCache creates a cache that prepares statements against
+// preparer, holding at most size statements. A size of 0 disables
+// caching entirely.
+func newStmtCache(preparer stmtPreparer, size int) *stmtCache {
+	if size <= 0 {
+		return nil
+	}
+
+	return &stmtCache{
+		size:     size,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		preparer: preparer,
+	}
+}
+
+// prepare returns a cached *sql.Stmt for statement, preparing and
+// inserting one on a cache miss, and evicting the least recently used
+// entry (closing its *sql.Stmt) if the cache is full.
+func (c *stmtCache) prepare(ctx context.Context, statement string) (*sql.Stmt, error) {
+	c.mu.Lock()
+
+	if el, ok := c.entries[statement]; ok {
+		c.order.MoveToFront(el)
+		stmt := el.Value.(*stmtCacheEntry).stmt
+		c.mu.Unlock()
+		return stmt, nil
+	}
+
+	c.mu.Unlock()
+
+	stmt, err := c.preparer.PrepareContext(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[statement]; ok {
+		c.order.MoveToFront(el)
+		stmt.Close()
+		return el.Value.(*stmtCacheEntry).stmt, nil
+	}
+
+	el := c.order.PushFront(&stmtCacheEntry{statement: statement, stmt: stmt})
+	c.entries[statement] = el
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		entry := oldest.Value.(*stmtCacheEntry)
+		delete(c.entries, entry.statement)
+		entry.stmt.Close()
+	}
+
+	return stmt, nil
+}
+
+// close closes every cached statement and empties the cache.
+func (c *stmtCache) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var firstErr error
+	for el := c.order.Front(); el != nil; el = el.Next() {
+		if err := el.Value.(*stmtCacheEntry).stmt.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+
+	return firstErr
+}
@@ -0,0 +1,88 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Fs02/grimoire/adapter/sql/sqltest"
+)
+
+func TestStmtCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	db := sqltest.Open()
+	defer db.Close()
+
+	ctx := context.Background()
+	cache := newStmtCache(db, 2)
+
+	first, err := cache.prepare(ctx, "SELECT 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.prepare(ctx, "SELECT 2"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A third distinct statement should evict "SELECT 1", the least
+	// recently used entry.
+	if _, err := cache.prepare(ctx, "SELECT 3"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := cache.entries["SELECT 1"]; ok {
+		t.Fatal("expected \"SELECT 1\" to have been evicted")
+	}
+
+	reprepared, err := cache.prepare(ctx, "SELECT 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reprepared == first {
+		t.Fatal("expected a freshly prepared *sql.Stmt after eviction, got the stale one")
+	}
+}
+
+func TestStmtCacheConcurrentPrepareIsRaceFree(t *testing.T) {
+	db := sqltest.Open()
+	defer db.Close()
+
+	ctx := context.Background()
+	cache := newStmtCache(db, 4)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.prepare(ctx, fmt.Sprintf("SELECT %d", i%4)); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestStmtCacheForInitializesOnceUnderConcurrency(t *testing.T) {
+	adapter := newTestAdapter(t)
+	adapter.Config.StmtCacheSize = 4
+
+	var wg sync.WaitGroup
+	caches := make([]*stmtCache, 50)
+	for i := range caches {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			caches[i] = adapter.stmtCacheFor()
+		}()
+	}
+	wg.Wait()
+
+	for i := 1; i < len(caches); i++ {
+		if caches[i] != caches[0] {
+			t.Fatal("expected every concurrent stmtCacheFor() call to return the same *stmtCache instance")
+		}
+	}
+}
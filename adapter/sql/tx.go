@@ -0,0 +1,72 @@
+package sql
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// txStatus tracks what has happened to the transaction or savepoint a
+// single Adapter instance represents.
+type txStatus int
+
+// Possible txStatus values.
+const (
+	txOpen txStatus = iota
+	txCommitted
+	txRolledBack
+)
+
+// ErrTxClosed is returned by Commit/Rollback (and their Context
+// variants) when called a second time on the same transaction or
+// savepoint.
+var ErrTxClosed = errors.New("sql: transaction already committed or rolled back")
+
+// txState is shared by the root Adapter of a transaction and every
+// Adapter produced by its nested Begin calls, so savepoint names come
+// from a single monotonically increasing counter per transaction
+// instead of a counter kept on each Adapter, which could repeat names
+// when two Begin calls nest off the same parent.
+type txState struct {
+	mu      sync.Mutex
+	counter int
+}
+
+// nextSavepoint returns the next globally unique savepoint name for
+// this transaction.
+func (s *txState) nextSavepoint() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counter++
+	return "s" + strconv.Itoa(s.counter)
+}
+
+// Transaction runs fn against a new transaction (or, if adapter is
+// already inside one, a nested savepoint) begun from adapter. It
+// commits on success, and rolls back if fn returns an error or panics,
+// re-panicking afterwards so the caller's own recover still observes
+// it. This mirrors the repo's usual `defer tx.Rollback()` idiom for
+// callers who'd rather hand over a closure.
+func (adapter *Adapter) Transaction(fn func(*Adapter) error) (err error) {
+	child, err := adapter.Begin()
+	if err != nil {
+		return err
+	}
+
+	tx := child.(*Adapter)
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
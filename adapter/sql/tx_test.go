@@ -0,0 +1,96 @@
+package sql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNestedBeginUsesDistinctSavepointNames(t *testing.T) {
+	adapter := newTestAdapter(t)
+
+	top, err := adapter.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer top.(*Adapter).Rollback()
+
+	first, err := top.(*Adapter).Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := top.(*Adapter).Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	firstName := first.(*Adapter).savepointName
+	secondName := second.(*Adapter).savepointName
+
+	if firstName == "" || secondName == "" {
+		t.Fatalf("expected non-empty savepoint names, got %q and %q", firstName, secondName)
+	}
+	if firstName == secondName {
+		t.Fatalf("expected sibling Begin() calls off the same parent to get distinct savepoint names, both got %q", firstName)
+	}
+}
+
+func TestCommitTwiceReturnsErrTxClosed(t *testing.T) {
+	adapter := newTestAdapter(t)
+
+	tx, err := adapter.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.(*Adapter).Commit(); err != nil {
+		t.Fatalf("first Commit() = %v, want nil", err)
+	}
+
+	if err := tx.(*Adapter).Commit(); !errors.Is(err, ErrTxClosed) {
+		t.Fatalf("second Commit() = %v, want %v", err, ErrTxClosed)
+	}
+}
+
+func TestRollbackAfterCommitReturnsErrTxClosed(t *testing.T) {
+	adapter := newTestAdapter(t)
+
+	tx, err := adapter.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.(*Adapter).Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want nil", err)
+	}
+
+	if err := tx.(*Adapter).Rollback(); !errors.Is(err, ErrTxClosed) {
+		t.Fatalf("Rollback() after Commit() = %v, want %v", err, ErrTxClosed)
+	}
+}
+
+func TestTransactionRollsBackOnError(t *testing.T) {
+	adapter := newTestAdapter(t)
+
+	wantErr := errors.New("boom")
+	err := adapter.Transaction(func(tx *Adapter) error {
+		return wantErr
+	})
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("Transaction() = %v, want %v", err, wantErr)
+	}
+}
+
+func TestTransactionRecoversPanicAndReraises(t *testing.T) {
+	adapter := newTestAdapter(t)
+
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Fatalf("recover() = %v, want %q to propagate out of Transaction()", p, "boom")
+		}
+	}()
+
+	adapter.Transaction(func(tx *Adapter) error {
+		panic("boom")
+	})
+}
@@ -0,0 +1,29 @@
+// Package sqlite3 provides the SQLite-specific sql.ColumnMapper.
+package sqlite3
+
+import (
+	"github.com/Fs02/grimoire/adapter/sql"
+)
+
+// ColumnMapper maps grimoire column types to their SQLite equivalent,
+// for use as sql.Config.ColumnMapper. SQLite's INTEGER PRIMARY KEY
+// already aliases rowid and auto-increments, so no trailing clause is
+// needed.
+func ColumnMapper(def sql.ColumnDefinition) (string, string) {
+	switch def.Type {
+	case sql.Int, sql.BigInt:
+		return "integer", ""
+	case sql.String, sql.Text:
+		return "text", ""
+	case sql.Bool:
+		return "boolean", ""
+	case sql.DateTime:
+		return "datetime", ""
+	case sql.Decimal:
+		return "real", ""
+	case sql.JSON:
+		return "text", ""
+	default:
+		return string(def.Type), ""
+	}
+}
@@ -0,0 +1,79 @@
+// Package rel provides a small CLI runner for sql.Migrator. Since
+// migrations are registered in Go code rather than discovered from
+// files on disk, applications wire their own main package that
+// registers migrations on a *sql.Migrator and calls Main with the
+// remaining command-line arguments:
+//
+//	func main() {
+//		migrator := sql.NewMigrator(adapter)
+//		migrator.Register(20210102150405, up, down)
+//		rel.Main(migrator, os.Args[1:])
+//	}
+package rel
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Fs02/grimoire/adapter/sql"
+)
+
+// Main dispatches a migrate subcommand (up, down, redo, status, step)
+// against migrator, printing the result to stdout and exiting the
+// process with a non-zero status on error.
+func Main(migrator *sql.Migrator, args []string) {
+	if err := Run(migrator, args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Run is the testable counterpart of Main: it returns the error instead
+// of exiting.
+func Run(migrator *sql.Migrator, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: rel migrate up|down|redo|status|step <n>")
+	}
+
+	if args[0] != "migrate" {
+		return fmt.Errorf("unknown command %q, expected \"migrate\"", args[0])
+	}
+
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	rest := fs.Args()
+	if len(rest) == 0 {
+		return fmt.Errorf("usage: rel migrate up|down|redo|status|step <n>")
+	}
+
+	switch rest[0] {
+	case "up":
+		return migrator.Up()
+	case "down":
+		return migrator.Down()
+	case "redo":
+		return migrator.Redo()
+	case "status":
+		version, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("current version: %d\n", version)
+		return nil
+	case "step":
+		if len(rest) != 2 {
+			return fmt.Errorf("usage: rel migrate step <n>")
+		}
+		var n int
+		if _, err := fmt.Sscanf(rest[1], "%d", &n); err != nil {
+			return fmt.Errorf("invalid step count %q: %w", rest[1], err)
+		}
+		return migrator.Step(n)
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q", rest[0])
+	}
+}